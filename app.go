@@ -8,7 +8,9 @@ import (
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"sync"
 	"syscall"
+	"time"
 
 	wruntime "github.com/wailsapp/wails/v2/pkg/runtime"
 )
@@ -17,6 +19,11 @@ import (
 type App struct {
 	ctx    context.Context
 	helper *OneNoteHelper
+
+	mu                sync.Mutex
+	exportCancel      context.CancelFunc
+	uploadTarget      UploadTarget
+	lastBackupSummary *BackupSummary
 }
 
 // FileItem represents a file or directory in the OneNote backup
@@ -33,6 +40,29 @@ type ExportResult struct {
 	Success      bool   `json:"success"`
 	Message      string `json:"message"`
 	ExportedPath string `json:"exportedPath,omitempty"`
+	Cancelled    bool   `json:"cancelled,omitempty"`
+	UploadedPath string `json:"uploadedPath,omitempty"`
+}
+
+// ProgressEvent is a single structured progress update emitted by the
+// OneNote helper while an export is running, one JSON object per line
+// prefixed with the progressSentinel.
+type ProgressEvent struct {
+	Kind         string `json:"kind"`
+	NotebookID   string `json:"notebookId,omitempty"`
+	NotebookName string `json:"notebookName,omitempty"`
+	Current      int    `json:"current"`
+	Total        int    `json:"total"`
+	BytesWritten int64  `json:"bytesWritten,omitempty"`
+	Stage        string `json:"stage,omitempty"`
+	Message      string `json:"message,omitempty"`
+}
+
+// EtaUpdate is emitted alongside ProgressEvent, carrying a smoothed
+// throughput estimate for the frontend's time-remaining display.
+type EtaUpdate struct {
+	SecondsRemaining float64 `json:"secondsRemaining"`
+	BytesPerSecond   float64 `json:"bytesPerSecond"`
 }
 
 // NotebookInfo represents a OneNote notebook
@@ -51,6 +81,45 @@ type VersionInfo struct {
 	OneNoteVersion   string `json:"oneNoteVersion"`
 }
 
+// SectionInfo represents a OneNote section within a notebook
+type SectionInfo struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	LastModified string `json:"lastModified"`
+}
+
+// PageInfo represents a single OneNote page within a section
+type PageInfo struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	LastModified string `json:"lastModified"`
+}
+
+// NotebookTree represents the section/page hierarchy of a notebook, used by
+// the frontend to render a checkbox tree for partial exports
+type NotebookTree struct {
+	NotebookID string        `json:"notebookId"`
+	Sections   []SectionNode `json:"sections"`
+}
+
+// SectionNode is a section together with its pages, as returned by GetNotebookTree
+type SectionNode struct {
+	SectionInfo
+	Pages []PageInfo `json:"pages"`
+}
+
+// These constants enumerate the export formats supported by ExportNotebookAs and
+// the per-hierarchy export methods. They map directly to OneNote's
+// PublishFormat enum, except "md" which is produced by exporting "mhtml" and
+// post-processing the result on the Go side.
+const (
+	FormatOnePkg = "onepkg"
+	FormatPDF    = "pdf"
+	FormatDOCX   = "docx"
+	FormatMHTML  = "mhtml"
+	FormatMD     = "md"
+)
+
 // NewApp creates a new App application struct
 func NewApp() *App {
 	return &App{}
@@ -70,6 +139,18 @@ func (a *App) startup(ctx context.Context) {
 	}
 }
 
+// shutdown is called when the app is closing. It gives the OneNote helper a
+// chance to terminate gracefully instead of leaving an orphaned process.
+func (a *App) shutdown(ctx context.Context) {
+	if a.helper == nil {
+		return
+	}
+
+	if err := a.helper.Close(); err != nil {
+		fmt.Printf("Warning: error closing OneNote Helper: %v\n", err)
+	}
+}
+
 // GetOneNoteBackupPath returns the path to the OneNote backup folder
 func (a *App) GetOneNoteBackupPath() string {
 	// Get the user's home directory
@@ -177,6 +258,215 @@ func (a *App) ExportBackup(destPath string) ExportResult {
 	return ExportResult{Success: true, Message: "Export completed successfully!"}
 }
 
+// ExportBackupIncremental exports the OneNote backup folder like ExportBackup,
+// but consults a manifest at <destPath>/.onenote-backup-manifest.json to
+// avoid recopying unchanged files. mode is one of:
+//   - "full": copy everything and (re)build the manifest from scratch
+//   - "incremental": skip files whose size+mtime still match the manifest
+//   - "verify": don't copy anything, just re-hash existing destination files
+//     against the manifest and report any that no longer match
+//
+// The counts from the run are available afterwards via GetLastBackupSummary.
+func (a *App) ExportBackupIncremental(destPath string, mode string) ExportResult {
+	if mode != "full" && mode != "incremental" && mode != "verify" {
+		return ExportResult{Success: false, Message: fmt.Sprintf("unknown backup mode: %s", mode)}
+	}
+
+	oneNotePath := a.GetOneNoteBackupPath()
+	if oneNotePath == "" {
+		return ExportResult{Success: false, Message: "Could not determine OneNote backup path"}
+	}
+
+	if err := os.MkdirAll(destPath, 0755); err != nil {
+		return ExportResult{Success: false, Message: fmt.Sprintf("Error creating destination directory: %v", err)}
+	}
+
+	manifest, err := loadManifest(destPath)
+	if err != nil {
+		return ExportResult{Success: false, Message: err.Error()}
+	}
+	if mode == "full" {
+		// Rebuild the manifest from scratch so every file is recorded fresh
+		// instead of being compared against (and reported as "changed"
+		// relative to) whatever a previous run left behind.
+		manifest.Files = make(map[string]manifestEntry)
+	}
+
+	summary := &BackupSummary{}
+
+	if mode == "verify" {
+		a.verifyManifest(manifest, destPath, summary)
+	} else {
+		seen := make(map[string]bool)
+		if err := a.copyDirectoryIncremental(oneNotePath, oneNotePath, destPath, manifest, mode, summary, seen); err != nil {
+			return ExportResult{Success: false, Message: fmt.Sprintf("Error copying files: %v", err)}
+		}
+		a.pruneRemovedEntries(manifest, seen, summary)
+
+		// Collapse any byte-identical .onepkg duplicates against the manifest
+		// entries copyDirectoryIncremental just (re)recorded, before saving,
+		// so the saved manifest already reflects any SameAs pointer and a
+		// later verify run never tries to re-hash a path dedupe removed.
+		// Scoped to destPath alone and never runs on the plain
+		// ExportAllNotebooks path.
+		if err := dedupeNotebookPackages(destPath, manifest); err != nil {
+			fmt.Printf("Warning: deduplicating notebook packages failed: %v\n", err)
+		}
+
+		if err := manifest.save(destPath); err != nil {
+			return ExportResult{Success: false, Message: err.Error()}
+		}
+
+		a.openFolder(destPath)
+	}
+
+	a.mu.Lock()
+	a.lastBackupSummary = summary
+	a.mu.Unlock()
+
+	message := fmt.Sprintf("Backup (%s) completed: %d new, %d changed, %d unchanged, %d removed",
+		mode, summary.New, summary.Changed, summary.Unchanged, summary.Removed)
+	if len(summary.Corrupted) > 0 {
+		message = fmt.Sprintf("Verify completed: %d file(s) corrupted or missing", len(summary.Corrupted))
+	}
+
+	return ExportResult{Success: true, Message: message, ExportedPath: destPath}
+}
+
+// GetLastBackupSummary returns the counts from the most recent
+// ExportBackupIncremental run, or nil if none has run yet this session.
+func (a *App) GetLastBackupSummary() *BackupSummary {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.lastBackupSummary
+}
+
+// ResolveNotebookPackagePath resolves fileName (a .onepkg previously written
+// into destPath by ExportBackupIncremental) to the path that actually holds
+// its bytes, following a dedupeNotebookPackages pointer if fileName itself
+// was collapsed into one. The frontend calls this before opening or
+// restoring a specific backed-up notebook package.
+func (a *App) ResolveNotebookPackagePath(destPath, fileName string) (string, error) {
+	return resolveNotebookPackage(destPath, fileName)
+}
+
+// copyDirectoryIncremental mirrors copyDirectory, but consults the manifest
+// to skip unchanged files in "incremental" mode and records each copied
+// file's hash for future runs and for "verify" mode.
+func (a *App) copyDirectoryIncremental(root, src, dst string, manifest *backupManifest, mode string, summary *BackupSummary, seen map[string]bool) error {
+	srcInfo, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dst, srcInfo.Mode()); err != nil {
+		return err
+	}
+
+	items, err := os.ReadDir(src)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		srcPath := filepath.Join(src, item.Name())
+		dstPath := filepath.Join(dst, item.Name())
+
+		if item.IsDir() {
+			if err := a.copyDirectoryIncremental(root, srcPath, dstPath, manifest, mode, summary, seen); err != nil {
+				return err
+			}
+			continue
+		}
+
+		relPath, err := filepath.Rel(root, srcPath)
+		if err != nil {
+			return err
+		}
+		seen[relPath] = true
+
+		info, err := item.Info()
+		if err != nil {
+			return err
+		}
+
+		if mode == "incremental" {
+			if entry, ok := manifest.Files[relPath]; ok && entry.unchanged(info) {
+				summary.Unchanged++
+				continue
+			}
+		}
+
+		if err := a.copyFile(srcPath, dstPath); err != nil {
+			return err
+		}
+
+		hash, err := hashFile(dstPath)
+		if err != nil {
+			return err
+		}
+
+		_, existed := manifest.Files[relPath]
+		manifest.Files[relPath] = manifestEntry{Size: info.Size(), ModTime: info.ModTime(), SHA256: hash}
+		if existed {
+			summary.Changed++
+		} else {
+			summary.New++
+		}
+	}
+
+	return nil
+}
+
+// pruneRemovedEntries drops manifest entries for files that no longer exist
+// in the source tree, counting them as removed.
+func (a *App) pruneRemovedEntries(manifest *backupManifest, seen map[string]bool, summary *BackupSummary) {
+	for relPath := range manifest.Files {
+		if !seen[relPath] {
+			delete(manifest.Files, relPath)
+			summary.Removed++
+		}
+	}
+}
+
+// verifyManifest re-hashes every file the manifest knows about and reports
+// any that are missing or whose content no longer matches.
+func (a *App) verifyManifest(manifest *backupManifest, destPath string, summary *BackupSummary) {
+	for relPath, entry := range manifest.Files {
+		// A SameAs entry was intentionally removed from disk by
+		// dedupeNotebookPackages; verify it by resolving the pointer chain
+		// instead of hashing the (deliberately absent) path directly.
+		if entry.SameAs != "" {
+			target, err := resolveNotebookPackageInManifest(manifest, destPath, relPath)
+			if err != nil {
+				summary.Corrupted = append(summary.Corrupted, relPath+" (sameAs pointer broken)")
+				continue
+			}
+			hash, err := hashFile(target)
+			if err != nil || hash != entry.SHA256 {
+				summary.Corrupted = append(summary.Corrupted, relPath)
+				continue
+			}
+			summary.Unchanged++
+			continue
+		}
+
+		fullPath := filepath.Join(destPath, relPath)
+
+		hash, err := hashFile(fullPath)
+		if err != nil {
+			summary.Corrupted = append(summary.Corrupted, relPath+" (missing)")
+			continue
+		}
+		if hash != entry.SHA256 {
+			summary.Corrupted = append(summary.Corrupted, relPath)
+			continue
+		}
+
+		summary.Unchanged++
+	}
+}
+
 // copyDirectory recursively copies a directory tree
 func (a *App) copyDirectory(src, dst string) error {
 	// Get properties of source directory
@@ -397,6 +687,235 @@ func (a *App) ExportNotebook(notebookID, destinationPath string) (*ExportResult,
 	return result, nil
 }
 
+// GetSections returns the sections of a notebook
+func (a *App) GetSections(notebookID string) ([]SectionInfo, error) {
+	if a.helper == nil {
+		return nil, fmt.Errorf("OneNote Helper is not available")
+	}
+
+	return a.helper.GetSections(notebookID)
+}
+
+// GetPages returns the pages of a section
+func (a *App) GetPages(sectionID string) ([]PageInfo, error) {
+	if a.helper == nil {
+		return nil, fmt.Errorf("OneNote Helper is not available")
+	}
+
+	return a.helper.GetPages(sectionID)
+}
+
+// GetNotebookTree returns the full section/page hierarchy of a notebook for
+// the frontend's checkbox tree
+func (a *App) GetNotebookTree(notebookID string) (*NotebookTree, error) {
+	if a.helper == nil {
+		return nil, fmt.Errorf("OneNote Helper is not available")
+	}
+
+	sections, err := a.helper.GetSections(notebookID)
+	if err != nil {
+		return nil, err
+	}
+
+	tree := &NotebookTree{NotebookID: notebookID}
+	for _, section := range sections {
+		pages, err := a.helper.GetPages(section.ID)
+		if err != nil {
+			return nil, err
+		}
+		tree.Sections = append(tree.Sections, SectionNode{SectionInfo: section, Pages: pages})
+	}
+
+	return tree, nil
+}
+
+// ExportNotebookAs exports a notebook in the requested format. Supported
+// formats are "onepkg", "pdf", "docx", "mhtml" and "md". For "md", the
+// helper is asked to produce MHTML and the result is converted to Markdown
+// on the Go side.
+func (a *App) ExportNotebookAs(notebookID, format, destinationPath string) (*ExportResult, error) {
+	if a.helper == nil {
+		return &ExportResult{
+			Success: false,
+			Message: "OneNote Helper is not available",
+		}, fmt.Errorf("OneNote Helper is not available")
+	}
+
+	result, err := a.exportAs(format, destinationPath, func(helperFormat, dest string) (*ExportResult, error) {
+		return a.helper.ExportNotebookAs(notebookID, helperFormat, dest)
+	})
+	if err != nil {
+		return &ExportResult{Success: false, Message: err.Error()}, err
+	}
+
+	if result.Success {
+		a.openFolder(destinationPath)
+	}
+
+	return result, nil
+}
+
+// ExportSectionAs exports a single section in the requested format
+func (a *App) ExportSectionAs(sectionID, format, destinationPath string) (*ExportResult, error) {
+	if a.helper == nil {
+		return &ExportResult{
+			Success: false,
+			Message: "OneNote Helper is not available",
+		}, fmt.Errorf("OneNote Helper is not available")
+	}
+
+	result, err := a.exportAs(format, destinationPath, func(helperFormat, dest string) (*ExportResult, error) {
+		return a.helper.ExportSection(sectionID, helperFormat, dest)
+	})
+	if err != nil {
+		return &ExportResult{Success: false, Message: err.Error()}, err
+	}
+
+	if result.Success {
+		a.openFolder(destinationPath)
+	}
+
+	return result, nil
+}
+
+// ExportPagesAs exports a batch of pages in the requested format
+func (a *App) ExportPagesAs(pageIDs []string, format, destinationPath string) (*ExportResult, error) {
+	if a.helper == nil {
+		return &ExportResult{
+			Success: false,
+			Message: "OneNote Helper is not available",
+		}, fmt.Errorf("OneNote Helper is not available")
+	}
+
+	result, err := a.exportAs(format, destinationPath, func(helperFormat, dest string) (*ExportResult, error) {
+		if len(pageIDs) == 1 {
+			return a.helper.ExportPage(pageIDs[0], helperFormat, dest)
+		}
+		return a.helper.ExportPageBatch(pageIDs, helperFormat, dest)
+	})
+	if err != nil {
+		return &ExportResult{Success: false, Message: err.Error()}, err
+	}
+
+	if result.Success {
+		a.openFolder(destinationPath)
+	}
+
+	return result, nil
+}
+
+// exportAs runs doExport with the requested format, transparently routing
+// "md" through an MHTML export followed by an HTML-to-Markdown conversion,
+// since the COM helper has no native Markdown publish format.
+func (a *App) exportAs(format, destinationPath string, doExport func(helperFormat, dest string) (*ExportResult, error)) (*ExportResult, error) {
+	if format != FormatMD {
+		return doExport(format, destinationPath)
+	}
+
+	mhtmlDir, err := os.MkdirTemp("", "onenote-mhtml-*")
+	if err != nil {
+		return nil, fmt.Errorf("error creating temporary directory: %w", err)
+	}
+	defer os.RemoveAll(mhtmlDir)
+
+	result, err := doExport(FormatMHTML, mhtmlDir)
+	if err != nil {
+		return nil, err
+	}
+	if !result.Success {
+		return result, nil
+	}
+
+	if err := os.MkdirAll(destinationPath, 0755); err != nil {
+		return nil, fmt.Errorf("error creating destination directory: %w", err)
+	}
+
+	if err := convertMHTMLTreeToMarkdown(mhtmlDir, destinationPath); err != nil {
+		return nil, fmt.Errorf("error converting to Markdown: %w", err)
+	}
+
+	return &ExportResult{Success: true, Message: "Export completed successfully!", ExportedPath: destinationPath}, nil
+}
+
+// getUploadTarget lazily creates the OneDrive upload target, since
+// constructing it doesn't require network access (the device-code flow only
+// runs on first Upload call).
+func (a *App) getUploadTarget() (UploadTarget, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.uploadTarget != nil {
+		return a.uploadTarget, nil
+	}
+
+	target, err := NewGraphOneDriveTarget()
+	if err != nil {
+		return nil, err
+	}
+
+	a.uploadTarget = target
+	return target, nil
+}
+
+// ExportAndUpload exports a notebook to a temporary local folder and then
+// uploads the resulting .onepkg to the given OneDrive folder, reporting
+// upload progress via the "upload-progress" event.
+func (a *App) ExportAndUpload(notebookID, remoteFolder string) (*ExportResult, error) {
+	if a.helper == nil {
+		return &ExportResult{
+			Success: false,
+			Message: "OneNote Helper is not available",
+		}, fmt.Errorf("OneNote Helper is not available")
+	}
+
+	target, err := a.getUploadTarget()
+	if err != nil {
+		return &ExportResult{Success: false, Message: fmt.Sprintf("Error setting up OneDrive upload: %v", err)}, err
+	}
+
+	tempDir, err := os.MkdirTemp("", "onenote-export-*")
+	if err != nil {
+		return &ExportResult{Success: false, Message: fmt.Sprintf("Error creating temporary directory: %v", err)}, err
+	}
+	defer os.RemoveAll(tempDir)
+
+	result, err := a.helper.ExportNotebook(notebookID, tempDir)
+	if err != nil {
+		return &ExportResult{Success: false, Message: err.Error()}, err
+	}
+	if !result.Success {
+		return result, nil
+	}
+
+	localFiles, err := os.ReadDir(tempDir)
+	if err != nil || len(localFiles) == 0 {
+		return &ExportResult{Success: false, Message: "Export succeeded but produced no output file"}, fmt.Errorf("no exported file found in %s", tempDir)
+	}
+	localPath := filepath.Join(tempDir, localFiles[0].Name())
+	remotePath := remoteFolder + "/" + localFiles[0].Name()
+
+	progress := func(sent, total int64) {
+		wruntime.EventsEmit(a.ctx, "upload-progress", map[string]interface{}{
+			"sent":  sent,
+			"total": total,
+		})
+	}
+
+	if err := target.Upload(a.ctx, localPath, remotePath, progress); err != nil {
+		return &ExportResult{
+			Success: false,
+			Message: fmt.Sprintf("Export succeeded but upload failed: %v", err),
+		}, err
+	}
+
+	return &ExportResult{
+		Success:      true,
+		Message:      "Export and upload completed successfully!",
+		ExportedPath: tempDir,
+		UploadedPath: remotePath,
+	}, nil
+}
+
 // ExportAllNotebooks exports all notebooks to the specified destination
 // Runs asynchronously in background, sending real-time progress events to frontend
 func (a *App) ExportAllNotebooks(destinationPath string) (*ExportResult, error) {
@@ -412,18 +931,41 @@ func (a *App) ExportAllNotebooks(destinationPath string) (*ExportResult, error)
 	go func() {
 		fmt.Println("DEBUG: Starting async export...")
 
-		// Progress callback that parses stderr output from C# helper and sends events to frontend
+		ctx, cancel := context.WithCancel(a.ctx)
+		a.mu.Lock()
+		a.exportCancel = cancel
+		a.mu.Unlock()
+		defer func() {
+			a.mu.Lock()
+			a.exportCancel = nil
+			a.mu.Unlock()
+			cancel()
+		}()
+
+		eta := newEtaTracker()
+
+		// Progress callback that parses structured progress lines from the
+		// C# helper and emits typed events to the frontend. Lines that
+		// aren't progress events are forwarded as raw log messages.
 		progressCallback := func(line string) {
-			// Emit the raw line as a progress update
-			fmt.Fprintf(os.Stderr, "[Progress] %s\n", line)
-			wruntime.EventsEmit(a.ctx, "export-progress", map[string]interface{}{
-				"message": line,
-				"type":    "status",
-			})
+			event, ok := parseProgressLine(line)
+			if !ok {
+				fmt.Fprintf(os.Stderr, "[Progress] %s\n", line)
+				wruntime.EventsEmit(a.ctx, "export-progress", map[string]interface{}{
+					"message": line,
+					"type":    "status",
+				})
+				return
+			}
+
+			wruntime.EventsEmit(a.ctx, "export-progress", event)
+			wruntime.EventsEmit(a.ctx, "export-eta", eta.update(event))
 		}
 
-		// Call C# helper with progress streaming
-		result, err := a.helper.ExportAllNotebooks(destinationPath, progressCallback)
+		// Call C# helper with progress streaming. Using the Ctx variant lets
+		// CancelExportGraceful ask the helper to stop cleanly instead of
+		// killing the process.
+		result, err := a.helper.ExportAllNotebooksCtx(ctx, destinationPath, progressCallback)
 
 		fmt.Println("DEBUG: Export finished, sending completion event...")
 
@@ -443,6 +985,7 @@ func (a *App) ExportAllNotebooks(destinationPath string) (*ExportResult, error)
 				"success":      result.Success,
 				"message":      result.Message,
 				"exportedPath": result.ExportedPath,
+				"cancelled":    result.Cancelled,
 			})
 		}
 	}()
@@ -454,6 +997,85 @@ func (a *App) ExportAllNotebooks(destinationPath string) (*ExportResult, error)
 	}, nil
 }
 
+// etaTracker computes a smoothed bytes/sec throughput estimate from a
+// stream of ProgressEvents, exponentially weighting recent samples so brief
+// stalls or bursts don't swing the reported ETA wildly.
+type etaTracker struct {
+	lastTime  time.Time
+	lastBytes int64
+	emaRate   float64
+	hasEma    bool
+}
+
+func newEtaTracker() *etaTracker {
+	return &etaTracker{}
+}
+
+// etaSmoothingFactor weights how much a new sample moves the moving average;
+// higher values track recent throughput more closely.
+const etaSmoothingFactor = 0.3
+
+// update folds the latest progress event into the tracker and returns the
+// current smoothed ETA.
+func (t *etaTracker) update(event ProgressEvent) EtaUpdate {
+	now := time.Now()
+
+	if !t.lastTime.IsZero() && event.BytesWritten > t.lastBytes {
+		elapsed := now.Sub(t.lastTime).Seconds()
+		if elapsed > 0 {
+			instantRate := float64(event.BytesWritten-t.lastBytes) / elapsed
+			if !t.hasEma {
+				t.emaRate = instantRate
+				t.hasEma = true
+			} else {
+				t.emaRate = etaSmoothingFactor*instantRate + (1-etaSmoothingFactor)*t.emaRate
+			}
+		}
+	}
+
+	t.lastTime = now
+	t.lastBytes = event.BytesWritten
+
+	var secondsRemaining float64
+	if t.emaRate > 0 && event.Total > 0 && event.Current > 0 && event.Current < event.Total {
+		bytesPerUnit := float64(event.BytesWritten) / float64(event.Current)
+		remainingUnits := float64(event.Total - event.Current)
+		secondsRemaining = (bytesPerUnit * remainingUnits) / t.emaRate
+	}
+
+	return EtaUpdate{SecondsRemaining: secondsRemaining, BytesPerSecond: t.emaRate}
+}
+
+// CancelExportGraceful requests cooperative cancellation of the running
+// export. It cancels the context passed to ExportAllNotebooksCtx, which
+// causes the helper to finish the current page/section, delete the partial
+// output and return a cancelled result - OneNote itself is never killed.
+// This is the preferred way to cancel; CancelExport remains as a hard-kill
+// escape hatch for when the helper stops responding.
+func (a *App) CancelExportGraceful() (*ExportResult, error) {
+	a.mu.Lock()
+	cancel := a.exportCancel
+	a.mu.Unlock()
+
+	if cancel == nil {
+		return &ExportResult{
+			Success: false,
+			Message: "No export is currently running",
+		}, fmt.Errorf("no export is currently running")
+	}
+
+	cancel()
+
+	wruntime.EventsEmit(a.ctx, "export-cancelled", map[string]interface{}{
+		"message": "Cancellation requested, finishing current page/section...",
+	})
+
+	return &ExportResult{
+		Success: true,
+		Message: "Cancellation requested",
+	}, nil
+}
+
 // CancelExport cancels a running export by killing both OneNoteHelper.exe and ONENOTE.EXE processes
 func (a *App) CancelExport() (*ExportResult, error) {
 	fmt.Println("DEBUG: CancelExport called - killing processes...")
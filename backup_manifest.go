@@ -0,0 +1,228 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// manifestFileName is the name of the manifest file kept in the destination
+// directory of an incremental backup.
+const manifestFileName = ".onenote-backup-manifest.json"
+
+// manifestEntry records what was last backed up for one relative path, used
+// to decide whether a file is unchanged, and to detect corruption in
+// "verify" mode. SameAs is set instead of the file existing on disk when
+// dedupeNotebookPackages has collapsed it into a pointer at another
+// relative path with identical content; Size/ModTime/SHA256 still describe
+// the content itself, so unchanged() works as before and verifyManifest
+// checks SHA256 against the file the SameAs chain resolves to.
+type manifestEntry struct {
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mtime"`
+	SHA256  string    `json:"sha256,omitempty"`
+	SameAs  string    `json:"sameAs,omitempty"`
+}
+
+// backupManifest maps a path relative to the backup destination to its last
+// known state.
+type backupManifest struct {
+	Files map[string]manifestEntry `json:"files"`
+}
+
+// BackupSummary reports what an incremental or verify run did, for display
+// in the UI via GetLastBackupSummary.
+type BackupSummary struct {
+	New       int      `json:"new"`
+	Changed   int      `json:"changed"`
+	Unchanged int      `json:"unchanged"`
+	Removed   int      `json:"removed"`
+	Corrupted []string `json:"corrupted,omitempty"`
+}
+
+func manifestPath(destPath string) string {
+	return filepath.Join(destPath, manifestFileName)
+}
+
+// loadManifest reads the manifest from destPath, returning an empty manifest
+// if none exists yet (e.g. the first run).
+func loadManifest(destPath string) (*backupManifest, error) {
+	data, err := os.ReadFile(manifestPath(destPath))
+	if os.IsNotExist(err) {
+		return &backupManifest{Files: make(map[string]manifestEntry)}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading backup manifest: %w", err)
+	}
+
+	var manifest backupManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("error parsing backup manifest: %w", err)
+	}
+	if manifest.Files == nil {
+		manifest.Files = make(map[string]manifestEntry)
+	}
+
+	return &manifest, nil
+}
+
+// save writes the manifest back to destPath as pretty-printed JSON.
+func (m *backupManifest) save(destPath string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding backup manifest: %w", err)
+	}
+
+	return os.WriteFile(manifestPath(destPath), data, 0644)
+}
+
+// hashFile streams a file through SHA-256 without loading it fully into memory.
+func hashFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, file); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// unchanged reports whether info still matches entry by size and mtime
+// alone (the cheap check used before falling back to a content hash).
+func (e manifestEntry) unchanged(info os.FileInfo) bool {
+	return e.Size == info.Size() && e.ModTime.Equal(info.ModTime())
+}
+
+// notebookPackageSuffix matches the " (1)", " (2)", ... suffix Windows/the
+// COM helper appends to avoid overwriting an existing .onepkg when a
+// notebook is exported again into a destination that already holds one, so
+// repeated backups of an unchanged notebook to the same folder accumulate
+// byte-identical copies under that naming scheme.
+var notebookPackageSuffix = regexp.MustCompile(`^(.*) \(\d+\)$`)
+
+// notebookPackageKey returns the stable identity of the notebook behind an
+// exported .onepkg's relative path, stripping any "(N)" duplicate-avoidance
+// suffix from the file name so repeated exports of the same notebook into
+// the same directory group together, without merging files that merely
+// share a name in different directories.
+func notebookPackageKey(relPath string) string {
+	dir := filepath.Dir(relPath)
+	stem := strings.TrimSuffix(filepath.Base(relPath), filepath.Ext(relPath))
+	if m := notebookPackageSuffix.FindStringSubmatch(stem); m != nil {
+		stem = m[1]
+	}
+	return filepath.Join(dir, stem)
+}
+
+// dedupeNotebookPackages looks at the .onepkg entries manifest already
+// records for destinationPath (populated by copyDirectoryIncremental earlier
+// in the same run, so their SHA256 reflects the previous run's hash for any
+// entry that didn't change) and, within each group of entries that belong to
+// the same notebook (per notebookPackageKey), collapses any that are
+// byte-identical to the oldest entry in the group. The oldest copy is always
+// left on disk untouched; a newer duplicate has its file deleted and its
+// manifest entry rewritten to point at the survivor via SameAs, so the
+// manifest (not a separate sidecar file) is always the source of truth for
+// where a notebook's bytes actually live — verifyManifest resolves SameAs
+// itself instead of trying to re-hash a path that was intentionally removed.
+func dedupeNotebookPackages(destinationPath string, manifest *backupManifest) error {
+	groups := make(map[string][]string)
+	for relPath, entry := range manifest.Files {
+		if entry.SameAs != "" || filepath.Ext(relPath) != ".onepkg" {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(destinationPath, relPath)); err != nil {
+			continue
+		}
+		key := notebookPackageKey(relPath)
+		groups[key] = append(groups[key], relPath)
+	}
+
+	for _, members := range groups {
+		if len(members) < 2 {
+			continue
+		}
+
+		sort.Slice(members, func(i, j int) bool {
+			return manifest.Files[members[i]].ModTime.Before(manifest.Files[members[j]].ModTime)
+		})
+
+		survivor := members[0]
+		survivorHash := manifest.Files[survivor].SHA256
+
+		for _, dup := range members[1:] {
+			if manifest.Files[dup].SHA256 != survivorHash {
+				continue
+			}
+			if _, err := os.Stat(filepath.Join(destinationPath, survivor)); err != nil {
+				continue
+			}
+
+			if err := os.Remove(filepath.Join(destinationPath, dup)); err != nil {
+				return fmt.Errorf("%s: %w", dup, err)
+			}
+
+			entry := manifest.Files[dup]
+			entry.SameAs = survivor
+			manifest.Files[dup] = entry
+		}
+	}
+
+	return nil
+}
+
+// resolveNotebookPackageInManifest follows relPath's SameAs chain within an
+// already-loaded manifest to the relative path that actually holds its
+// bytes, returning its absolute path within destPath.
+func resolveNotebookPackageInManifest(manifest *backupManifest, destPath, relPath string) (string, error) {
+	visited := make(map[string]bool)
+	for {
+		if visited[relPath] {
+			return "", fmt.Errorf("%s: sameAs pointer cycle in backup manifest", relPath)
+		}
+		visited[relPath] = true
+
+		entry, ok := manifest.Files[relPath]
+		if !ok {
+			return "", fmt.Errorf("%s: not found in backup manifest", relPath)
+		}
+		if entry.SameAs == "" {
+			break
+		}
+		relPath = entry.SameAs
+	}
+
+	fullPath := filepath.Join(destPath, relPath)
+	if _, err := os.Stat(fullPath); err != nil {
+		return "", fmt.Errorf("%s: pointer target is missing: %w", relPath, err)
+	}
+
+	return fullPath, nil
+}
+
+// resolveNotebookPackage resolves relPath (a .onepkg previously recorded in
+// destPath's backup manifest) to the path that actually holds its bytes,
+// following a dedupeNotebookPackages SameAs pointer if relPath was collapsed
+// into one. It's the read-side counterpart of dedupeNotebookPackages:
+// anything that wants to open or restore a previously backed-up .onepkg
+// must go through here instead of assuming relPath itself holds the data.
+func resolveNotebookPackage(destPath, relPath string) (string, error) {
+	manifest, err := loadManifest(destPath)
+	if err != nil {
+		return "", err
+	}
+	return resolveNotebookPackageInManifest(manifest, destPath, relPath)
+}
@@ -0,0 +1,373 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/microsoft"
+)
+
+// oneDriveClientID is the Azure AD app registration's client ID used for the
+// device-code OAuth2 flow. It identifies the app, not a secret - OneDrive
+// device-code flows for public clients don't use a client secret.
+const oneDriveClientID = "8e8b1b8e-2e5a-4b6a-9f0b-0f6a9f9c0f1e"
+
+// graphUploadSessionThreshold is the cutoff above which Upload uses a
+// resumable upload session instead of the simple PUT /content endpoint, per
+// the Graph API's own guidance for files larger than 4 MiB.
+const graphUploadSessionThreshold = 4 * 1024 * 1024
+
+// graphChunkSize must be a multiple of 320 KiB, as required by the Graph
+// resumable upload session API.
+const graphChunkSize = 320 * 1024 * 32 // 10 MiB
+
+// UploadTarget uploads a local file to a remote destination, reporting
+// progress as it goes. GraphOneDriveTarget is the only implementation today,
+// but the interface keeps App.ExportAndUpload independent of the backing
+// storage provider.
+type UploadTarget interface {
+	Upload(ctx context.Context, localPath, remotePath string, progress func(sent, total int64)) error
+}
+
+// GraphOneDriveTarget uploads files to the signed-in user's OneDrive via the
+// Microsoft Graph API.
+type GraphOneDriveTarget struct {
+	oauthConfig *oauth2.Config
+	tokenPath   string
+	httpClient  *http.Client
+}
+
+// NewGraphOneDriveTarget creates an upload target backed by Microsoft Graph.
+// The refresh token is cached under %APPDATA%\OneNoteBackupExporter so the
+// user only has to complete the device-code flow once.
+func NewGraphOneDriveTarget() (*GraphOneDriveTarget, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return nil, fmt.Errorf("konnte Konfigurationsverzeichnis nicht ermitteln: %w", err)
+	}
+
+	tokenPath := filepath.Join(configDir, "OneNoteBackupExporter", "token.json")
+
+	return &GraphOneDriveTarget{
+		oauthConfig: &oauth2.Config{
+			ClientID: oneDriveClientID,
+			Endpoint: microsoft.AzureADEndpoint("consumers"),
+			Scopes:   []string{"Files.ReadWrite", "offline_access"},
+		},
+		tokenPath:  tokenPath,
+		httpClient: &http.Client{Timeout: 60 * time.Second},
+	}, nil
+}
+
+// tokenSource returns an oauth2.TokenSource backed by the cached refresh
+// token if one exists, or drives an interactive device-code flow otherwise.
+// Either way, the resulting (possibly refreshed) token is written back to
+// tokenPath so future runs don't need to re-authenticate.
+func (g *GraphOneDriveTarget) tokenSource(ctx context.Context) (oauth2.TokenSource, error) {
+	token, err := g.loadCachedToken()
+	if err != nil {
+		token, err = g.authenticateDeviceCode(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	source := g.oauthConfig.TokenSource(ctx, token)
+	return &persistingTokenSource{source: source, onRefresh: g.saveToken}, nil
+}
+
+// authenticateDeviceCode runs the OAuth2 device-code flow: the user is shown
+// a verification URL and a short code to enter, and the call blocks until
+// they complete it (or it expires).
+func (g *GraphOneDriveTarget) authenticateDeviceCode(ctx context.Context) (*oauth2.Token, error) {
+	deviceAuth, err := g.oauthConfig.DeviceAuth(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("fehler beim Starten des Geräte-Anmeldevorgangs: %w", err)
+	}
+
+	fmt.Printf("Zum Verbinden mit OneDrive bitte %s aufrufen und den Code %s eingeben.\n",
+		deviceAuth.VerificationURI, deviceAuth.UserCode)
+
+	token, err := g.oauthConfig.DeviceAccessToken(ctx, deviceAuth)
+	if err != nil {
+		return nil, fmt.Errorf("fehler bei der Geräte-Anmeldung: %w", err)
+	}
+
+	if err := g.saveToken(token); err != nil {
+		fmt.Printf("Warning: konnte Token nicht zwischenspeichern: %v\n", err)
+	}
+
+	return token, nil
+}
+
+func (g *GraphOneDriveTarget) loadCachedToken() (*oauth2.Token, error) {
+	data, err := os.ReadFile(g.tokenPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var token oauth2.Token
+	if err := json.Unmarshal(data, &token); err != nil {
+		return nil, fmt.Errorf("fehler beim Parsen des zwischengespeicherten Tokens: %w", err)
+	}
+
+	return &token, nil
+}
+
+func (g *GraphOneDriveTarget) saveToken(token *oauth2.Token) error {
+	if err := os.MkdirAll(filepath.Dir(g.tokenPath), 0700); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(g.tokenPath, data, 0600)
+}
+
+// persistingTokenSource wraps an oauth2.TokenSource and writes the token to
+// disk whenever it changes (i.e. after a refresh), so the next run can pick
+// up the new refresh token.
+type persistingTokenSource struct {
+	source    oauth2.TokenSource
+	onRefresh func(*oauth2.Token) error
+	last      string
+}
+
+func (p *persistingTokenSource) Token() (*oauth2.Token, error) {
+	token, err := p.source.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	if token.RefreshToken != p.last {
+		p.last = token.RefreshToken
+		if err := p.onRefresh(token); err != nil {
+			fmt.Printf("Warning: konnte aktualisiertes Token nicht zwischenspeichern: %v\n", err)
+		}
+	}
+
+	return token, nil
+}
+
+// Upload uploads localPath to remotePath (a OneDrive path rooted at
+// /me/drive/root:), choosing a simple PUT for small files and a resumable
+// upload session for files above graphUploadSessionThreshold.
+func (g *GraphOneDriveTarget) Upload(ctx context.Context, localPath, remotePath string, progress func(sent, total int64)) error {
+	source, err := g.tokenSource(ctx)
+	if err != nil {
+		return err
+	}
+
+	info, err := os.Stat(localPath)
+	if err != nil {
+		return fmt.Errorf("fehler beim Lesen der Datei: %w", err)
+	}
+
+	if info.Size() <= graphUploadSessionThreshold {
+		return g.uploadSimple(ctx, source, localPath, remotePath, info.Size(), progress)
+	}
+
+	return g.uploadResumable(ctx, source, localPath, remotePath, info.Size(), progress)
+}
+
+func (g *GraphOneDriveTarget) uploadSimple(ctx context.Context, source oauth2.TokenSource, localPath, remotePath string, size int64, progress func(sent, total int64)) error {
+	file, err := os.Open(localPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	url := fmt.Sprintf("https://graph.microsoft.com/v1.0/me/drive/root:/%s:/content", strings.TrimPrefix(remotePath, "/"))
+
+	if err := g.doWithRetry(ctx, func() (*http.Request, error) {
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			return nil, err
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPut, url, file)
+		if err != nil {
+			return nil, err
+		}
+		req.ContentLength = size
+		return req, nil
+	}, source); err != nil {
+		return err
+	}
+
+	if progress != nil {
+		progress(size, size)
+	}
+
+	return nil
+}
+
+// uploadResumable opens a Graph upload session and PUTs the file in
+// graphChunkSize-aligned chunks, retrying 5xx responses with exponential
+// backoff and honoring Retry-After.
+func (g *GraphOneDriveTarget) uploadResumable(ctx context.Context, source oauth2.TokenSource, localPath, remotePath string, size int64, progress func(sent, total int64)) error {
+	sessionURL, err := g.createUploadSession(ctx, source, remotePath)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(localPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	buf := make([]byte, graphChunkSize)
+	var sent int64
+
+	for sent < size {
+		n, readErr := io.ReadFull(file, buf)
+		if readErr != nil && readErr != io.ErrUnexpectedEOF && readErr != io.EOF {
+			return fmt.Errorf("fehler beim Lesen der Datei: %w", readErr)
+		}
+
+		chunk := buf[:n]
+		start := sent
+		end := sent + int64(n) - 1
+
+		err := g.putChunk(ctx, sessionURL, chunk, start, end, size)
+		if err != nil {
+			return err
+		}
+
+		sent += int64(n)
+		if progress != nil {
+			progress(sent, size)
+		}
+	}
+
+	return nil
+}
+
+func (g *GraphOneDriveTarget) createUploadSession(ctx context.Context, source oauth2.TokenSource, remotePath string) (string, error) {
+	url := fmt.Sprintf("https://graph.microsoft.com/v1.0/me/drive/root:/%s:/createUploadSession", strings.TrimPrefix(remotePath, "/"))
+
+	var sessionURL string
+	err := g.doWithRetry(ctx, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader(`{"item":{"@microsoft.graph.conflictBehavior":"replace"}}`))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		return req, nil
+	}, source, func(resp *http.Response) error {
+		var session struct {
+			UploadURL string `json:"uploadUrl"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&session); err != nil {
+			return fmt.Errorf("fehler beim Parsen der Upload-Session: %w", err)
+		}
+		sessionURL = session.UploadURL
+		return nil
+	})
+
+	return sessionURL, err
+}
+
+func (g *GraphOneDriveTarget) putChunk(ctx context.Context, sessionURL string, chunk []byte, start, end, total int64) error {
+	return g.doWithRetry(ctx, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPut, sessionURL, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(chunk))
+		req.ContentLength = int64(len(chunk))
+		req.Header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end, total))
+		return req, nil
+	}, nil)
+}
+
+const maxGraphRetries = 5
+
+// doWithRetry sends the request built by buildReq, retrying 5xx responses
+// with exponential backoff and honoring Retry-After. When source is non-nil
+// the request is authenticated with a bearer token (upload-session PUTs are
+// pre-authorized by the session URL and pass nil). onSuccess, if given, is
+// called with the response body for the caller to consume before it's
+// closed.
+func (g *GraphOneDriveTarget) doWithRetry(ctx context.Context, buildReq func() (*http.Request, error), source oauth2.TokenSource, onSuccess ...func(*http.Response) error) error {
+	var lastErr error
+
+	for attempt := 0; attempt < maxGraphRetries; attempt++ {
+		req, err := buildReq()
+		if err != nil {
+			return err
+		}
+
+		if source != nil {
+			token, err := source.Token()
+			if err != nil {
+				return fmt.Errorf("fehler beim Abrufen des Zugriffstokens: %w", err)
+			}
+			req.Header.Set("Authorization", "Bearer "+token.AccessToken)
+		}
+
+		resp, err := g.httpClient.Do(req)
+		if err != nil {
+			lastErr = err
+			g.backoff(ctx, attempt, 0)
+			continue
+		}
+
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			defer resp.Body.Close()
+			if len(onSuccess) > 0 {
+				return onSuccess[0](resp)
+			}
+			io.Copy(io.Discard, resp.Body)
+			return nil
+		}
+
+		retryAfter := parseRetryAfter(resp.Header.Get("Retry-After"))
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		lastErr = fmt.Errorf("Graph-API-Fehler %d: %s", resp.StatusCode, string(body))
+
+		if resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+			return lastErr
+		}
+
+		g.backoff(ctx, attempt, retryAfter)
+	}
+
+	return fmt.Errorf("maximale Anzahl an Wiederholungsversuchen erreicht: %w", lastErr)
+}
+
+func (g *GraphOneDriveTarget) backoff(ctx context.Context, attempt int, retryAfter time.Duration) {
+	delay := retryAfter
+	if delay == 0 {
+		delay = time.Duration(math.Pow(2, float64(attempt))) * time.Second
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(delay):
+	}
+}
+
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	return 0
+}
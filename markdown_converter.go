@@ -0,0 +1,304 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// mhtmlPart is a single MIME part of an MHTML document (the page's HTML body
+// or one of its embedded resources, e.g. images).
+type mhtmlPart struct {
+	contentType        string
+	contentLocation    string
+	contentID          string
+	contentTransferEnc string
+	body               []byte
+}
+
+type mhtmlDocument struct {
+	parts []mhtmlPart
+}
+
+// parseMHTMLParts parses a complete MHTML file (a MIME message with a
+// multipart/related body) into its constituent parts.
+func parseMHTMLParts(raw []byte) (*mhtmlDocument, error) {
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("fehler beim Parsen der MHTML-Datei: %w", err)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, fmt.Errorf("fehler beim Parsen des Content-Type: %w", err)
+	}
+
+	if !strings.HasPrefix(mediaType, "multipart/") {
+		return nil, fmt.Errorf("unerwarteter MHTML Content-Type: %s", mediaType)
+	}
+
+	doc := &mhtmlDocument{}
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("fehler beim Lesen eines MIME-Teils: %w", err)
+		}
+
+		body, err := io.ReadAll(part)
+		if err != nil {
+			return nil, err
+		}
+
+		doc.parts = append(doc.parts, mhtmlPart{
+			contentType:        part.Header.Get("Content-Type"),
+			contentLocation:    part.Header.Get("Content-Location"),
+			contentID:          strings.Trim(part.Header.Get("Content-ID"), "<>"),
+			contentTransferEnc: part.Header.Get("Content-Transfer-Encoding"),
+			body:               body,
+		})
+	}
+
+	return doc, nil
+}
+
+// htmlAndImages splits a parsed document into its text/html part (the page
+// body) and its image parts (embedded resources).
+func (d *mhtmlDocument) htmlAndImages() (string, []mhtmlPart) {
+	var html string
+	var images []mhtmlPart
+
+	for _, part := range d.parts {
+		mediaType, _, _ := mime.ParseMediaType(part.contentType)
+		switch {
+		case strings.HasPrefix(mediaType, "text/html") && html == "":
+			decoded, err := decodeMHTMLBody(part)
+			if err == nil {
+				html = string(decoded)
+			}
+		case strings.HasPrefix(mediaType, "image/"):
+			images = append(images, part)
+		}
+	}
+
+	return html, images
+}
+
+// decodeMHTMLBody decodes a part's body according to its
+// Content-Transfer-Encoding (quoted-printable or base64; anything else is
+// assumed to already be raw bytes).
+func decodeMHTMLBody(part mhtmlPart) ([]byte, error) {
+	switch strings.ToLower(strings.TrimSpace(part.contentTransferEnc)) {
+	case "quoted-printable":
+		return io.ReadAll(quotedprintable.NewReader(bytes.NewReader(part.body)))
+	case "base64":
+		decoded, err := io.ReadAll(base64.NewDecoder(base64.StdEncoding, bufio.NewReader(bytes.NewReader(part.body))))
+		if err != nil {
+			return nil, fmt.Errorf("fehler beim Base64-Dekodieren: %w", err)
+		}
+		return decoded, nil
+	default:
+		return part.body, nil
+	}
+}
+
+// convertMHTMLTreeToMarkdown walks srcDir for .mht/.mhtml files produced by
+// the OneNote helper and writes a corresponding .md file for each one into
+// destDir, extracting embedded images into a sibling "assets" folder.
+func convertMHTMLTreeToMarkdown(srcDir, destDir string) error {
+	entries, err := os.ReadDir(srcDir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		srcPath := filepath.Join(srcDir, entry.Name())
+
+		if entry.IsDir() {
+			if err := convertMHTMLTreeToMarkdown(srcPath, filepath.Join(destDir, entry.Name())); err != nil {
+				return err
+			}
+			continue
+		}
+
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if ext != ".mht" && ext != ".mhtml" {
+			continue
+		}
+
+		if err := os.MkdirAll(destDir, 0755); err != nil {
+			return err
+		}
+
+		pageStem := strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))
+		mdName := pageStem + ".md"
+		if err := convertMHTMLFileToMarkdown(srcPath, filepath.Join(destDir, mdName), filepath.Join(destDir, "assets"), pageStem); err != nil {
+			return fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+	}
+
+	return nil
+}
+
+// convertMHTMLFileToMarkdown parses a single MHTML page, extracts embedded
+// images into assetsDir and writes the converted Markdown to mdPath.
+// pagePrefix namespaces that page's asset filenames (assetsDir is shared by
+// every page converted into the same destDir) so two pages' images never
+// collide or overwrite one another.
+func convertMHTMLFileToMarkdown(mhtmlPath, mdPath, assetsDir, pagePrefix string) error {
+	raw, err := os.ReadFile(mhtmlPath)
+	if err != nil {
+		return err
+	}
+
+	parts, err := parseMHTMLParts(raw)
+	if err != nil {
+		return err
+	}
+
+	html, images := parts.htmlAndImages()
+	if html == "" {
+		return fmt.Errorf("keine HTML-Nutzlast in MHTML-Datei gefunden")
+	}
+
+	var assetRefs map[string]string
+	if len(images) > 0 {
+		if err := os.MkdirAll(assetsDir, 0755); err != nil {
+			return err
+		}
+		assetRefs, err = writeImageAssets(images, assetsDir, pagePrefix)
+		if err != nil {
+			return err
+		}
+	}
+
+	markdown := htmlToMarkdown(html, assetRefs)
+	return os.WriteFile(mdPath, []byte(markdown), 0644)
+}
+
+// writeImageAssets decodes and writes each embedded image to assetsDir,
+// returning a map from its original Content-Location/cid to the relative
+// "assets/<file>" path used in the generated Markdown. Filenames are
+// prefixed with pagePrefix because assetsDir is shared across every page
+// converted into the same destination folder.
+func writeImageAssets(images []mhtmlPart, assetsDir, pagePrefix string) (map[string]string, error) {
+	refs := make(map[string]string, len(images))
+
+	for i, img := range images {
+		data, err := decodeMHTMLBody(img)
+		if err != nil {
+			return nil, err
+		}
+
+		name := fmt.Sprintf("%s-image%d%s", pagePrefix, i+1, extensionForContentType(img.contentType))
+		if err := os.WriteFile(filepath.Join(assetsDir, name), data, 0644); err != nil {
+			return nil, err
+		}
+
+		relPath := "assets/" + name
+		if img.contentLocation != "" {
+			refs[img.contentLocation] = relPath
+		}
+		if img.contentID != "" {
+			refs["cid:"+img.contentID] = relPath
+		}
+	}
+
+	return refs, nil
+}
+
+func extensionForContentType(contentType string) string {
+	switch {
+	case strings.Contains(contentType, "png"):
+		return ".png"
+	case strings.Contains(contentType, "gif"):
+		return ".gif"
+	case strings.Contains(contentType, "bmp"):
+		return ".bmp"
+	default:
+		return ".jpg"
+	}
+}
+
+var (
+	tagStyle    = regexp.MustCompile(`(?is)<style[^>]*>.*?</style>`)
+	tagScript   = regexp.MustCompile(`(?is)<script[^>]*>.*?</script>`)
+	tagHeading  = regexp.MustCompile(`(?is)<h[1-6][^>]*>(.*?)</h[1-6]>`)
+	tagBold     = regexp.MustCompile(`(?is)<(?:b|strong)[^>]*>(.*?)</(?:b|strong)>`)
+	tagItalic   = regexp.MustCompile(`(?is)<(?:i|em)[^>]*>(.*?)</(?:i|em)>`)
+	tagListItem = regexp.MustCompile(`(?is)<li[^>]*>(.*?)</li>`)
+	tagBreak    = regexp.MustCompile(`(?is)<(?:br|/p|/div)\s*/?>`)
+	tagImage    = regexp.MustCompile(`(?is)<img[^>]*\bsrc=["']([^"']+)["'][^>]*>`)
+	tagAnyLeft  = regexp.MustCompile(`(?is)<[^>]+>`)
+)
+
+// htmlToMarkdown does a best-effort conversion of a OneNote page's HTML body
+// into Markdown, rewriting <img> references to the local assets produced by
+// writeImageAssets. It intentionally only handles the small subset of HTML
+// that OneNote's publish output actually emits.
+func htmlToMarkdown(html string, assetRefs map[string]string) string {
+	body := html
+	if idx := strings.Index(strings.ToLower(body), "<body"); idx >= 0 {
+		if end := strings.Index(body[idx:], ">"); end >= 0 {
+			body = body[idx+end+1:]
+		}
+	}
+	if idx := strings.LastIndex(strings.ToLower(body), "</body>"); idx >= 0 {
+		body = body[:idx]
+	}
+
+	body = tagScript.ReplaceAllString(body, "")
+	body = tagStyle.ReplaceAllString(body, "")
+
+	body = tagImage.ReplaceAllStringFunc(body, func(m string) string {
+		match := tagImage.FindStringSubmatch(m)
+		src := match[1]
+		if ref, ok := assetRefs[src]; ok {
+			return fmt.Sprintf("![](%s)", ref)
+		}
+		return fmt.Sprintf("![](%s)", src)
+	})
+
+	body = tagHeading.ReplaceAllString(body, "\n## $1\n")
+	body = tagBold.ReplaceAllString(body, "**$1**")
+	body = tagItalic.ReplaceAllString(body, "_$1_")
+	body = tagListItem.ReplaceAllString(body, "- $1\n")
+	body = tagBreak.ReplaceAllString(body, "\n")
+	body = tagAnyLeft.ReplaceAllString(body, "")
+
+	body = strings.ReplaceAll(body, "&nbsp;", " ")
+	body = strings.ReplaceAll(body, "&amp;", "&")
+	body = strings.ReplaceAll(body, "&lt;", "<")
+	body = strings.ReplaceAll(body, "&gt;", ">")
+
+	lines := strings.Split(body, "\n")
+	var out []string
+	blank := false
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			if !blank {
+				out = append(out, "")
+			}
+			blank = true
+			continue
+		}
+		out = append(out, trimmed)
+		blank = false
+	}
+
+	return strings.TrimSpace(strings.Join(out, "\n")) + "\n"
+}
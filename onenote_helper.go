@@ -2,16 +2,40 @@ package main
 
 import (
 	"bufio"
-	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"sync"
 	"syscall"
+	"time"
 )
 
+// progressSentinel prefixes structured progress lines emitted by the C#
+// helper on stdout, e.g. `##PROGRESS## {"kind":"page","current":3,...}`.
+// Everything else on stdout that isn't a JSON-RPC response is forwarded to
+// the caller's progress callback as a raw log message.
+const progressSentinel = "##PROGRESS## "
+
+// parseProgressLine extracts a ProgressEvent from a sentinel-prefixed line,
+// reporting ok=false for anything that isn't one (plain log output).
+func parseProgressLine(line string) (event ProgressEvent, ok bool) {
+	payload, found := strings.CutPrefix(line, progressSentinel)
+	if !found {
+		return ProgressEvent{}, false
+	}
+
+	if err := json.Unmarshal([]byte(payload), &event); err != nil {
+		return ProgressEvent{}, false
+	}
+
+	return event, true
+}
+
 // JSON-RPC structures
 type jsonRpcRequest struct {
 	Method string                 `json:"method"`
@@ -30,14 +54,39 @@ type jsonRpcError struct {
 	Message string `json:"message"`
 }
 
-// OneNoteHelper manages communication with the C# helper program
+// shutdownGracePeriod is how long Close() waits, once the Shutdown RPC has
+// returned, for the helper process to exit before falling back to taskkill.
+const shutdownGracePeriod = 5 * time.Second
+
+// callIdleTimeout bounds how long call() waits for a response while no
+// activity at all (neither the reply nor a progress line) is seen on
+// stdout. Long-running RPCs like ExportAllNotebooks keep resetting this via
+// their progress lines, so only a genuinely stuck or malformed reply (e.g.
+// one missing its "id") trips it.
+const callIdleTimeout = 30 * time.Second
+
+// callIdlePollInterval is how often call() re-checks lastActivity while waiting.
+const callIdlePollInterval = 1 * time.Second
+
+// OneNoteHelper manages communication with the long-lived C# helper process.
+// A single OneNoteHelper.exe child is started once and kept alive across
+// calls; requests and responses are correlated via the JSON-RPC "id" field.
 type OneNoteHelper struct {
 	helperPath string
-	mu         sync.Mutex
-	requestID  int
+
+	mu               sync.Mutex
+	cmd              *exec.Cmd
+	stdin            io.WriteCloser
+	requestID        int
+	pending          map[int]chan jsonRpcResponse
+	progressCallback func(string)
+	closing          bool
+	processExited    chan struct{}
+	lastActivity     time.Time
 }
 
-// NewOneNoteHelper creates a new helper instance
+// NewOneNoteHelper creates a new helper instance and starts the backing
+// OneNoteHelper.exe process
 func NewOneNoteHelper() (*OneNoteHelper, error) {
 	// Find the helper executable
 	// First check in OneNoteHelper/bin/Release/net6.0-windows/
@@ -73,20 +122,148 @@ func NewOneNoteHelper() (*OneNoteHelper, error) {
 		return nil, fmt.Errorf("OneNoteHelper.exe nicht gefunden. Bitte zuerst das C# Helper-Programm kompilieren (cd OneNoteHelper && dotnet build -c Release)")
 	}
 
-	return &OneNoteHelper{
+	h := &OneNoteHelper{
 		helperPath: helperPath,
 		requestID:  1,
-	}, nil
+		pending:    make(map[int]chan jsonRpcResponse),
+	}
+
+	if err := h.startProcess(); err != nil {
+		return nil, err
+	}
+
+	return h, nil
+}
+
+// startProcess launches OneNoteHelper.exe, wires up the stdin/stdout pipes
+// and starts the reader goroutine that dispatches responses and forwards
+// progress lines. It also arms a supervisor that restarts the process if it
+// exits unexpectedly (i.e. not via Close()).
+func (h *OneNoteHelper) startProcess() error {
+	cmd := exec.Command(h.helperPath)
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		HideWindow:    true,
+		CreationFlags: 0x08000000, // CREATE_NO_WINDOW
+	}
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return fmt.Errorf("fehler beim Erstellen der stdin-Pipe: %w", err)
+	}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("fehler beim Erstellen der stdout-Pipe: %w", err)
+	}
+
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("fehler beim Starten des Helpers: %w", err)
+	}
+
+	h.mu.Lock()
+	h.cmd = cmd
+	h.stdin = stdin
+	h.processExited = make(chan struct{})
+	h.lastActivity = time.Now()
+	h.mu.Unlock()
+
+	go h.readLoop(stdout)
+	go h.superviseProcess(cmd, h.processExited)
+
+	return nil
+}
+
+// readLoop scans stdout line-by-line. Lines that parse as a JSON-RPC response
+// are dispatched to the waiting caller via its pending channel; everything
+// else is forwarded as a progress event to the current progress callback.
+func (h *OneNoteHelper) readLoop(stdout io.ReadCloser) {
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+
+		h.mu.Lock()
+		h.lastActivity = time.Now()
+		h.mu.Unlock()
+
+		var response jsonRpcResponse
+		if err := json.Unmarshal([]byte(line), &response); err == nil && response.ID != 0 {
+			h.mu.Lock()
+			ch, ok := h.pending[response.ID]
+			if ok {
+				delete(h.pending, response.ID)
+			}
+			cb := h.progressCallback
+			h.mu.Unlock()
+
+			if ok {
+				ch <- response
+				continue
+			}
+			// No one waiting for this ID (e.g. already timed out) - fall
+			// through and treat it as a progress line so nothing is lost.
+			_ = cb
+		}
+
+		h.mu.Lock()
+		cb := h.progressCallback
+		h.mu.Unlock()
+		if cb != nil {
+			cb(line)
+		}
+	}
 }
 
-// call executes a JSON-RPC call to the helper program
+// superviseProcess waits for the helper process to exit and, unless the exit
+// was requested via Close(), restarts it so future calls keep working.
+func (h *OneNoteHelper) superviseProcess(cmd *exec.Cmd, exited chan struct{}) {
+	cmd.Wait()
+	close(exited)
+
+	h.mu.Lock()
+	closing := h.closing
+	// Fail any calls still waiting on the dead process.
+	pending := h.pending
+	h.pending = make(map[int]chan jsonRpcResponse)
+	h.mu.Unlock()
+
+	for _, ch := range pending {
+		ch <- jsonRpcResponse{Error: &jsonRpcError{Message: "Helper-Prozess wurde unerwartet beendet"}}
+	}
+
+	if closing {
+		return
+	}
+
+	fmt.Println("Warning: OneNoteHelper.exe exited unexpectedly, restarting...")
+	if err := h.startProcess(); err != nil {
+		fmt.Printf("ERROR: konnte OneNoteHelper.exe nicht neu starten: %v\n", err)
+	}
+}
+
+// call sends a JSON-RPC request over the persistent stdin pipe and blocks
+// until the matching response arrives on the reader goroutine.
 func (h *OneNoteHelper) call(method string, params map[string]interface{}) (json.RawMessage, error) {
 	h.mu.Lock()
+	if h.stdin == nil {
+		h.mu.Unlock()
+		return nil, fmt.Errorf("Helper-Prozess ist nicht gestartet")
+	}
+
 	reqID := h.requestID
 	h.requestID++
+
+	respCh := make(chan jsonRpcResponse, 1)
+	h.pending[reqID] = respCh
+	stdin := h.stdin
 	h.mu.Unlock()
 
-	// Create request
 	request := jsonRpcRequest{
 		Method: method,
 		Params: params,
@@ -98,38 +275,63 @@ func (h *OneNoteHelper) call(method string, params map[string]interface{}) (json
 		return nil, fmt.Errorf("fehler beim Erstellen der Anfrage: %w", err)
 	}
 
-	// Execute helper program
-	cmd := exec.Command(h.helperPath)
-	cmd.Stdin = bytes.NewReader(requestJSON)
-
-	// Hide the console window (Windows only)
-	cmd.SysProcAttr = &syscall.SysProcAttr{
-		HideWindow:    true,
-		CreationFlags: 0x08000000, // CREATE_NO_WINDOW
+	if _, err := stdin.Write(append(requestJSON, '\n')); err != nil {
+		h.mu.Lock()
+		delete(h.pending, reqID)
+		h.mu.Unlock()
+		return nil, fmt.Errorf("fehler beim Senden der Anfrage: %w", err)
 	}
 
-	var stdout bytes.Buffer
-	cmd.Stdout = &stdout
-	// Forward stderr directly to console for debugging (C# helper writes diagnostics there)
-	cmd.Stderr = os.Stderr
+	ticker := time.NewTicker(callIdlePollInterval)
+	defer ticker.Stop()
 
-	err = cmd.Run()
-	if err != nil {
-		return nil, fmt.Errorf("fehler beim Ausführen des Helpers: %w", err)
+	for {
+		select {
+		case response := <-respCh:
+			if response.Error != nil {
+				return nil, fmt.Errorf("RPC-Fehler %d: %s", response.Error.Code, response.Error.Message)
+			}
+			return response.Result, nil
+
+		case <-ticker.C:
+			h.mu.Lock()
+			idle := time.Since(h.lastActivity)
+			h.mu.Unlock()
+
+			if idle > callIdleTimeout {
+				h.mu.Lock()
+				delete(h.pending, reqID)
+				h.mu.Unlock()
+				return nil, fmt.Errorf("Zeitüberschreitung: keine Antwort vom Helper-Prozess auf %q erhalten", method)
+			}
+		}
 	}
+}
 
-	// Parse response
-	var response jsonRpcResponse
-	if err := json.Unmarshal(stdout.Bytes(), &response); err != nil {
-		return nil, fmt.Errorf("fehler beim Parsen der Antwort: %w\nOutput: %s", err, stdout.String())
+// Close gracefully terminates the helper process. It first asks the helper
+// to shut down via the Shutdown RPC and waits up to shutdownGracePeriod for
+// it to exit on its own; if it does not, it falls back to taskkill.
+func (h *OneNoteHelper) Close() error {
+	h.mu.Lock()
+	h.closing = true
+	exited := h.processExited
+	h.mu.Unlock()
+
+	if exited == nil {
+		return nil
 	}
 
-	// Check for RPC error
-	if response.Error != nil {
-		return nil, fmt.Errorf("RPC-Fehler %d: %s", response.Error.Code, response.Error.Message)
+	if _, err := h.call("Shutdown", nil); err != nil {
+		fmt.Printf("Warning: Shutdown-RPC fehlgeschlagen: %v\n", err)
 	}
 
-	return response.Result, nil
+	select {
+	case <-exited:
+		return nil
+	case <-time.After(shutdownGracePeriod):
+		fmt.Println("Warning: Helper hat sich nicht rechtzeitig beendet, erzwinge Beendigung")
+		return killProcessByName("OneNoteHelper.exe")
+	}
 }
 
 // GetVersion returns version info from the helper
@@ -162,6 +364,44 @@ func (h *OneNoteHelper) GetNotebooks() ([]NotebookInfo, error) {
 	return notebooks, nil
 }
 
+// GetSections returns the sections of a notebook
+func (h *OneNoteHelper) GetSections(notebookID string) ([]SectionInfo, error) {
+	params := map[string]interface{}{
+		"notebookId": notebookID,
+	}
+
+	result, err := h.call("GetSections", params)
+	if err != nil {
+		return nil, err
+	}
+
+	var sections []SectionInfo
+	if err := json.Unmarshal(result, &sections); err != nil {
+		return nil, fmt.Errorf("fehler beim Parsen der Abschnitte: %w", err)
+	}
+
+	return sections, nil
+}
+
+// GetPages returns the pages of a section
+func (h *OneNoteHelper) GetPages(sectionID string) ([]PageInfo, error) {
+	params := map[string]interface{}{
+		"sectionId": sectionID,
+	}
+
+	result, err := h.call("GetPages", params)
+	if err != nil {
+		return nil, err
+	}
+
+	var pages []PageInfo
+	if err := json.Unmarshal(result, &pages); err != nil {
+		return nil, fmt.Errorf("fehler beim Parsen der Seiten: %w", err)
+	}
+
+	return pages, nil
+}
+
 // ExportNotebook exports a single notebook to .onepkg format
 func (h *OneNoteHelper) ExportNotebook(notebookID, destinationPath string) (*ExportResult, error) {
 	params := map[string]interface{}{
@@ -182,85 +422,191 @@ func (h *OneNoteHelper) ExportNotebook(notebookID, destinationPath string) (*Exp
 	return &exportResult, nil
 }
 
-// ExportAllNotebooks exports all notebooks with real-time progress streaming
-func (h *OneNoteHelper) ExportAllNotebooks(destinationPath string, progressCallback func(string)) (*ExportResult, error) {
+// ExportNotebookAs exports a notebook in the given format ("onepkg", "pdf",
+// "docx" or "mhtml") via Application.Publish on the helper side
+func (h *OneNoteHelper) ExportNotebookAs(notebookID, format, destinationPath string) (*ExportResult, error) {
 	params := map[string]interface{}{
+		"notebookId":      notebookID,
+		"format":          format,
 		"destinationPath": destinationPath,
 	}
 
-	h.mu.Lock()
-	reqID := h.requestID
-	h.requestID++
-	h.mu.Unlock()
+	result, err := h.call("ExportNotebookAs", params)
+	if err != nil {
+		return nil, err
+	}
 
-	// Create request
-	request := jsonRpcRequest{
-		Method: "ExportAllNotebooks",
-		Params: params,
-		ID:     reqID,
+	var exportResult ExportResult
+	if err := json.Unmarshal(result, &exportResult); err != nil {
+		return nil, fmt.Errorf("fehler beim Parsen des Exportergebnisses: %w", err)
 	}
 
-	requestJSON, err := json.Marshal(request)
+	return &exportResult, nil
+}
+
+// ExportSection exports a single section in the given format
+func (h *OneNoteHelper) ExportSection(sectionID, format, destinationPath string) (*ExportResult, error) {
+	params := map[string]interface{}{
+		"sectionId":       sectionID,
+		"format":          format,
+		"destinationPath": destinationPath,
+	}
+
+	result, err := h.call("ExportSection", params)
 	if err != nil {
-		return nil, fmt.Errorf("fehler beim Erstellen der Anfrage: %w", err)
+		return nil, err
 	}
 
-	// Execute helper program
-	cmd := exec.Command(h.helperPath)
-	cmd.Stdin = bytes.NewReader(requestJSON)
+	var exportResult ExportResult
+	if err := json.Unmarshal(result, &exportResult); err != nil {
+		return nil, fmt.Errorf("fehler beim Parsen des Exportergebnisses: %w", err)
+	}
 
-	// Hide the console window (Windows only)
-	cmd.SysProcAttr = &syscall.SysProcAttr{
-		HideWindow:    true,
-		CreationFlags: 0x08000000, // CREATE_NO_WINDOW
+	return &exportResult, nil
+}
+
+// ExportPage exports a single page in the given format
+func (h *OneNoteHelper) ExportPage(pageID, format, destinationPath string) (*ExportResult, error) {
+	params := map[string]interface{}{
+		"pageId":          pageID,
+		"format":          format,
+		"destinationPath": destinationPath,
+	}
+
+	result, err := h.call("ExportPage", params)
+	if err != nil {
+		return nil, err
 	}
 
-	var stdout bytes.Buffer
-	cmd.Stdout = &stdout
+	var exportResult ExportResult
+	if err := json.Unmarshal(result, &exportResult); err != nil {
+		return nil, fmt.Errorf("fehler beim Parsen des Exportergebnisses: %w", err)
+	}
 
-	// Capture stderr for real-time progress updates
-	stderrPipe, err := cmd.StderrPipe()
+	return &exportResult, nil
+}
+
+// ExportPageBatch exports several pages in the given format into a single destination
+func (h *OneNoteHelper) ExportPageBatch(pageIDs []string, format, destinationPath string) (*ExportResult, error) {
+	params := map[string]interface{}{
+		"pageIds":         pageIDs,
+		"format":          format,
+		"destinationPath": destinationPath,
+	}
+
+	result, err := h.call("ExportPageBatch", params)
 	if err != nil {
-		return nil, fmt.Errorf("fehler beim Erstellen der stderr-Pipe: %w", err)
+		return nil, err
 	}
 
-	// Start the command
-	if err := cmd.Start(); err != nil {
-		return nil, fmt.Errorf("fehler beim Starten des Helpers: %w", err)
+	var exportResult ExportResult
+	if err := json.Unmarshal(result, &exportResult); err != nil {
+		return nil, fmt.Errorf("fehler beim Parsen des Exportergebnisses: %w", err)
 	}
 
-	// Read stderr in real-time and send to callback
-	go func() {
-		scanner := bufio.NewScanner(stderrPipe)
-		for scanner.Scan() {
-			line := scanner.Text()
-			if progressCallback != nil {
-				progressCallback(line)
-			}
-			// Also print to console for debugging
-			fmt.Fprintf(os.Stderr, "%s\n", line)
-		}
+	return &exportResult, nil
+}
+
+// ExportAllNotebooks exports all notebooks with real-time progress streaming.
+// Progress lines emitted by the helper while the RPC is in flight are
+// forwarded to progressCallback as they arrive, interleaved with the final
+// result instead of being buffered until the process exits.
+func (h *OneNoteHelper) ExportAllNotebooks(destinationPath string, progressCallback func(string)) (*ExportResult, error) {
+	h.mu.Lock()
+	h.progressCallback = progressCallback
+	h.mu.Unlock()
+
+	defer func() {
+		h.mu.Lock()
+		h.progressCallback = nil
+		h.mu.Unlock()
 	}()
 
-	// Wait for command to complete
-	err = cmd.Wait()
+	params := map[string]interface{}{
+		"destinationPath": destinationPath,
+	}
+
+	result, err := h.call("ExportAllNotebooks", params)
 	if err != nil {
-		return nil, fmt.Errorf("fehler beim Ausführen des Helpers: %w", err)
+		return nil, err
 	}
 
-	// Parse response
-	var response jsonRpcResponse
-	if err := json.Unmarshal(stdout.Bytes(), &response); err != nil {
-		return nil, fmt.Errorf("fehler beim Parsen der Antwort: %w\nOutput: %s", err, stdout.String())
+	var exportResult ExportResult
+	if err := json.Unmarshal(result, &exportResult); err != nil {
+		return nil, fmt.Errorf("fehler beim Parsen des Exportergebnisses: %w", err)
+	}
+
+	return &exportResult, nil
+}
+
+// cancelAckGracePeriod is how long ExportAllNotebooksCtx waits for the
+// helper to acknowledge a Cancel RPC by returning from ExportAllNotebooks
+// before falling back to killing the process.
+const cancelAckGracePeriod = 10 * time.Second
+
+// ExportAllNotebooksCtx behaves like ExportAllNotebooks, but cancels
+// cooperatively: when ctx is done, it sends a Cancel RPC over the same
+// persistent connection instead of killing OneNoteHelper.exe / ONENOTE.EXE.
+// The helper finishes the page/section it is currently writing, deletes any
+// partial .onepkg and returns a result with Cancelled set. If the helper
+// doesn't acknowledge within cancelAckGracePeriod, the process is killed as
+// a fallback so the caller is never left waiting forever.
+func (h *OneNoteHelper) ExportAllNotebooksCtx(ctx context.Context, destinationPath string, progressCallback func(string)) (*ExportResult, error) {
+	h.mu.Lock()
+	h.progressCallback = progressCallback
+	h.mu.Unlock()
+
+	defer func() {
+		h.mu.Lock()
+		h.progressCallback = nil
+		h.mu.Unlock()
+	}()
+
+	params := map[string]interface{}{
+		"destinationPath": destinationPath,
+	}
+
+	type callOutcome struct {
+		result json.RawMessage
+		err    error
+	}
+	done := make(chan callOutcome, 1)
+	go func() {
+		result, err := h.call("ExportAllNotebooks", params)
+		done <- callOutcome{result, err}
+	}()
+
+	var outcome callOutcome
+	select {
+	case outcome = <-done:
+	case <-ctx.Done():
+		// Fire the Cancel RPC without waiting on it: call()'s own idle
+		// timeout is reset by the export's still-streaming progress lines,
+		// so waiting on it here could block well past cancelAckGracePeriod
+		// and the forced-kill fallback below would never run on schedule.
+		go func() {
+			if _, err := h.call("Cancel", nil); err != nil {
+				fmt.Printf("Warning: Cancel-RPC fehlgeschlagen: %v\n", err)
+			}
+		}()
+
+		select {
+		case outcome = <-done:
+		case <-time.After(cancelAckGracePeriod):
+			fmt.Println("Warning: Helper hat den Abbruch nicht rechtzeitig bestätigt, erzwinge Beendigung")
+			if err := killProcessByName("OneNoteHelper.exe"); err != nil {
+				fmt.Printf("Warning: Failed to kill OneNoteHelper.exe: %v\n", err)
+			}
+			return &ExportResult{Success: false, Message: "Export cancelled (forced)", Cancelled: true}, nil
+		}
 	}
 
-	// Check for RPC error
-	if response.Error != nil {
-		return nil, fmt.Errorf("RPC-Fehler %d: %s", response.Error.Code, response.Error.Message)
+	if outcome.err != nil {
+		return nil, outcome.err
 	}
 
 	var exportResult ExportResult
-	if err := json.Unmarshal(response.Result, &exportResult); err != nil {
+	if err := json.Unmarshal(outcome.result, &exportResult); err != nil {
 		return nil, fmt.Errorf("fehler beim Parsen des Exportergebnisses: %w", err)
 	}
 